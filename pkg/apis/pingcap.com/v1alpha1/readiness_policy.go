@@ -0,0 +1,52 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import "k8s.io/apimachinery/pkg/util/intstr"
+
+// ReadinessPolicy overrides the default PD -> TiKV -> TiDB sync ordering gates that
+// defaultTidbClusterControl.UpdateTidbCluster otherwise enforces. The zero value preserves the
+// existing behavior: wait for every PD replica to be healthy and every TiKV store to be up before
+// moving on to the next component.
+type ReadinessPolicy struct {
+	// PDQuorumOnly allows TiKV and TiDB sync to proceed once a PD quorum (majority of spec.PD.Replicas)
+	// is healthy, instead of waiting for all PD replicas.
+	// +optional
+	PDQuorumOnly bool `json:"pdQuorumOnly,omitempty"`
+
+	// TiKVMinAvailable overrides the number (or percentage) of TiKV stores that must be up before TiDB
+	// and meta sync proceed. Leave unset to require all of spec.TiKV.Replicas.
+	// +optional
+	TiKVMinAvailable intstr.IntOrString `json:"tikvMinAvailable,omitempty"`
+
+	// SkipTiKVGate skips waiting on TiKV store availability entirely, for PD-only clusters.
+	// +optional
+	SkipTiKVGate bool `json:"skipTiKVGate,omitempty"`
+}
+
+// DeepCopyInto is a deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessPolicy) DeepCopyInto(out *ReadinessPolicy) {
+	*out = *in
+	out.TiKVMinAvailable = in.TiKVMinAvailable
+}
+
+// DeepCopy is a deepcopy function, copying the receiver, creating a new ReadinessPolicy.
+func (in *ReadinessPolicy) DeepCopy() *ReadinessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,178 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	apps "k8s.io/api/apps/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TidbCluster is the control script's spec for a TiDB cluster.
+type TidbCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TidbClusterSpec   `json:"spec"`
+	Status TidbClusterStatus `json:"status,omitempty"`
+}
+
+// TidbClusterSpec describes the attributes that a user creates on a TidbCluster.
+type TidbClusterSpec struct {
+	PD   PDSpec   `json:"pd,omitempty"`
+	TiKV TiKVSpec `json:"tikv,omitempty"`
+	TiDB TiDBSpec `json:"tidb,omitempty"`
+
+	// ReadinessPolicy overrides the default PD -> TiKV -> TiDB sync ordering gates. See the
+	// ReadinessPolicy doc comment for the default behavior when this is left unset.
+	// +optional
+	ReadinessPolicy ReadinessPolicy `json:"readinessPolicy,omitempty"`
+}
+
+// TidbClusterStatus represents the current status of a TidbCluster.
+type TidbClusterStatus struct {
+	PD   PDStatus   `json:"pd,omitempty"`
+	TiKV TiKVStatus `json:"tikv,omitempty"`
+}
+
+// PDSpec contains details of PD members.
+type PDSpec struct {
+	Replicas int32 `json:"replicas"`
+}
+
+// TiKVSpec contains details of TiKV members.
+type TiKVSpec struct {
+	Replicas int32 `json:"replicas"`
+}
+
+// TiDBSpec contains details of TiDB members.
+type TiDBSpec struct {
+	Replicas int32 `json:"replicas"`
+}
+
+// MemberPhase is the current state of a member.
+type MemberPhase string
+
+const (
+	// NormalPhase represents a member is in normal state.
+	NormalPhase MemberPhase = "Normal"
+	// UpgradePhase represents a member is in upgrade state.
+	UpgradePhase MemberPhase = "Upgrade"
+)
+
+// PDStatus is the PD status of a TidbCluster.
+type PDStatus struct {
+	Phase       MemberPhase             `json:"phase,omitempty"`
+	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	Members     map[string]PDMember     `json:"members,omitempty"`
+}
+
+// PDMember is PD member
+type PDMember struct {
+	Name   string `json:"name"`
+	Health bool   `json:"health"`
+}
+
+// TiKVStatus is the TiKV status of a TidbCluster.
+type TiKVStatus struct {
+	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	Stores      map[string]TiKVStore    `json:"stores,omitempty"`
+}
+
+// TiKVStore is TiKV store status
+type TiKVStore struct {
+	PodName string    `json:"podName"`
+	State   TiKVState `json:"state"`
+}
+
+// TiKVState is the state of a TiKV store.
+type TiKVState string
+
+const (
+	// TiKVStateUp represents status of Up of TiKV
+	TiKVStateUp TiKVState = "Up"
+	// TiKVStateDown represents status of Down of TiKV
+	TiKVStateDown TiKVState = "Down"
+)
+
+// DeepCopyObject is a deepcopy function, copying the receiver, creating a new TidbCluster.
+func (in *TidbCluster) DeepCopyObject() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopy is a deepcopy function, copying the receiver, creating a new TidbCluster.
+func (in *TidbCluster) DeepCopy() *TidbCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbCluster)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return out
+}
+
+// DeepCopyInto is a deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
+	*out = *in
+	in.ReadinessPolicy.DeepCopyInto(&out.ReadinessPolicy)
+}
+
+// DeepCopy is a deepcopy function, copying the receiver, creating a new TidbClusterStatus.
+func (in *TidbClusterStatus) DeepCopy() *TidbClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is a deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
+	*out = *in
+	in.PD.DeepCopyInto(&out.PD)
+	in.TiKV.DeepCopyInto(&out.TiKV)
+}
+
+// DeepCopyInto is a deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDStatus) DeepCopyInto(out *PDStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		out.StatefulSet = in.StatefulSet.DeepCopy()
+	}
+	if in.Members != nil {
+		out.Members = make(map[string]PDMember, len(in.Members))
+		for k, v := range in.Members {
+			out.Members[k] = v
+		}
+	}
+}
+
+// DeepCopyInto is a deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVStatus) DeepCopyInto(out *TiKVStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		out.StatefulSet = in.StatefulSet.DeepCopy()
+	}
+	if in.Stores != nil {
+		out.Stores = make(map[string]TiKVStore, len(in.Stores))
+		for k, v := range in.Stores {
+			out.Stores[k] = v
+		}
+	}
+}
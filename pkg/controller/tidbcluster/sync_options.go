@@ -0,0 +1,96 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+)
+
+// SyncOptionsAnnotation is the annotation operators can set on a TidbCluster to steer a single
+// reconcile, borrowing the compare-options / sync-options annotation pattern from declarative GitOps
+// reconcilers.
+const SyncOptionsAnnotation = "pingcap.com/sync-options"
+
+// Component names recognized by the Skip directive of the sync-options annotation. Force takes effect
+// only for pd and tikv, the components that have a readiness gate.
+const (
+	syncComponentReclaimPolicy = "reclaim"
+	syncComponentPD            = "pd"
+	syncComponentTiKV          = "tikv"
+	syncComponentTiDB          = "tidb"
+	syncComponentMeta          = "meta"
+)
+
+// SyncOptions is the parsed form of the pingcap.com/sync-options annotation. The zero value (returned
+// when the annotation is absent) preserves the default pipeline: every manager runs, every readiness
+// gate is enforced, and reclaim-policy validation runs.
+type SyncOptions struct {
+	// Skip lists components whose Sync call should be bypassed for this reconcile.
+	Skip map[string]bool
+	// Force lists components whose readiness gate should be ignored for this reconcile.
+	Force map[string]bool
+	// Validate controls whether reclaim-policy checks run. Defaults to true.
+	Validate bool
+}
+
+func newDefaultSyncOptions() *SyncOptions {
+	return &SyncOptions{
+		Skip:     make(map[string]bool),
+		Force:    make(map[string]bool),
+		Validate: true,
+	}
+}
+
+// ParseSyncOptions parses the pingcap.com/sync-options annotation on tc into a SyncOptions. The
+// annotation value is a ';'-separated list of directives, e.g. "Skip=tikv,meta;Force=pd;Validate=false".
+// An absent or empty annotation parses to the default SyncOptions.
+func ParseSyncOptions(tc *v1alpha1.TidbCluster) (*SyncOptions, error) {
+	opts := newDefaultSyncOptions()
+
+	raw, ok := tc.Annotations[SyncOptionsAnnotation]
+	if !ok || strings.TrimSpace(raw) == "" {
+		return opts, nil
+	}
+
+	for _, directive := range strings.Split(raw, ";") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		kv := strings.SplitN(directive, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid %s directive %q: expected key=value", SyncOptionsAnnotation, directive)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "Skip":
+			for _, component := range strings.Split(value, ",") {
+				opts.Skip[strings.TrimSpace(component)] = true
+			}
+		case "Force":
+			for _, component := range strings.Split(value, ",") {
+				opts.Force[strings.TrimSpace(component)] = true
+			}
+		case "Validate":
+			opts.Validate = value != "false"
+		default:
+			return nil, fmt.Errorf("invalid %s directive: unknown key %q", SyncOptionsAnnotation, key)
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,352 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbcluster
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/manager/member"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+)
+
+// ControlInterface implements the control logic for updating TidbClusters and their children StatefulSets.
+// It is implemented as an interface to allow for extensions that provide different semantics.
+// Currently, there is only one implementation.
+type ControlInterface interface {
+	// UpdateTidbCluster implements the control logic for StatefulSet creation, update, and deletion
+	UpdateTidbCluster(*v1alpha1.TidbCluster) error
+	// DryRunUpdateTidbCluster runs the same reconciliation pipeline as UpdateTidbCluster but does not
+	// persist any changes; it returns a ClusterDiff describing what would have been done.
+	DryRunUpdateTidbCluster(*v1alpha1.TidbCluster) (*ClusterDiff, error)
+}
+
+// NewDefaultTidbClusterControl returns a new instance of the default implementation TidbClusterControlInterface that
+// implements the documented semantics for TidbClusters.
+func NewDefaultTidbClusterControl(
+	tcControl controller.TidbClusterControlInterface,
+	pdMemberManager manager.Manager,
+	tikvMemberManager manager.Manager,
+	tidbMemberManager manager.Manager,
+	reclaimPolicyManager manager.Manager,
+	metaManager manager.Manager,
+	orphanPodsCleaner member.OrphanPodsCleaner,
+	recorder record.EventRecorder) ControlInterface {
+	return &defaultTidbClusterControl{
+		tcControl,
+		pdMemberManager,
+		tikvMemberManager,
+		tidbMemberManager,
+		reclaimPolicyManager,
+		metaManager,
+		orphanPodsCleaner,
+		recorder,
+	}
+}
+
+type defaultTidbClusterControl struct {
+	tcControl            controller.TidbClusterControlInterface
+	pdMemberManager      manager.Manager
+	tikvMemberManager    manager.Manager
+	tidbMemberManager    manager.Manager
+	reclaimPolicyManager manager.Manager
+	metaManager          manager.Manager
+	orphanPodsCleaner    member.OrphanPodsCleaner
+	recorder             record.EventRecorder
+}
+
+// ComponentDiff describes the StatefulSet/Service/ConfigMap mutations a single member manager intends to
+// make, without having made them. It is an alias of manager.ComponentDiff: the type lives in pkg/manager
+// so that member/meta manager implementations can return it from Plan without importing this package.
+type ComponentDiff = manager.ComponentDiff
+
+// ClusterDiff aggregates the per-component diffs produced by a dry run of the reconciliation pipeline.
+//
+// It deliberately does not report PD member or TiKV store transitions: those only change when a manager's
+// real Sync talks to the live PD/TiKV API, and a dry run by design never calls Sync, so there would be
+// nothing to compare against tc's existing status.
+type ClusterDiff struct {
+	ReclaimPolicy *ComponentDiff
+	PD            *ComponentDiff
+	TiKV          *ComponentDiff
+	TiDB          *ComponentDiff
+	Meta          *ComponentDiff
+}
+
+// Planner is implemented by member managers that support computing an intended set of mutations without
+// applying them. Managers that do not implement Planner are skipped during a dry run. It is an alias of
+// manager.Planner; see ComponentDiff for why the interface lives in pkg/manager.
+type Planner = manager.Planner
+
+func (tcc *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster) error {
+	var errs []error
+	oldStatus := tc.Status.DeepCopy()
+
+	if err := tcc.updateTidbCluster(tc); err != nil {
+		errs = append(errs, err)
+	}
+
+	if !apiequality.Semantic.DeepEqual(&tc.Status, oldStatus) {
+		_, err := tcc.tcControl.UpdateTidbCluster(tc.DeepCopy(), &tc.Status, oldStatus)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errorutils.NewAggregate(errs)
+}
+
+// DryRunUpdateTidbCluster runs the reclaim-policy / PD / TiKV / TiDB / meta manager pipeline against a
+// copy of tc and reports what it would have changed, without writing the copy's status back to the API
+// server and without calling Sync on any member manager.
+func (tcc *defaultTidbClusterControl) DryRunUpdateTidbCluster(tc *v1alpha1.TidbCluster) (*ClusterDiff, error) {
+	tcCopy := tc.DeepCopy()
+	diff := &ClusterDiff{}
+
+	var err error
+	diff.ReclaimPolicy, err = tcc.planManager(tcc.reclaimPolicyManager, tcCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.PD, err = tcc.planManager(tcc.pdMemberManager, tcCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tcc.waitForPDClusterRunning(tcCopy); err != nil {
+		return diff, err
+	}
+
+	diff.TiKV, err = tcc.planManager(tcc.tikvMemberManager, tcCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tcc.waitForTiKVClusterRunning(tcCopy); err != nil {
+		return diff, err
+	}
+
+	diff.TiDB, err = tcc.planManager(tcc.tidbMemberManager, tcCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.Meta, err = tcc.planManager(tcc.metaManager, tcCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+// planManager computes the ComponentDiff for a single manager without mutating cluster state on the API
+// server. Managers that don't implement Planner contribute an empty diff.
+func (tcc *defaultTidbClusterControl) planManager(mgr manager.Manager, tc *v1alpha1.TidbCluster) (*ComponentDiff, error) {
+	planner, ok := mgr.(Planner)
+	if !ok {
+		return &ComponentDiff{}, nil
+	}
+	return planner.Plan(tc)
+}
+
+func (tcc *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster) error {
+	opts, err := ParseSyncOptions(tc)
+	if err != nil {
+		return err
+	}
+
+	// syncing all PVs managed by operator's reclaim policy to Retain
+	if !opts.Validate {
+		tcc.recordSyncOptionsSkip(tc, "reclaim policy validation", "Validate=false")
+	} else if opts.Skip[syncComponentReclaimPolicy] {
+		tcc.recordSyncOptionsSkip(tc, syncComponentReclaimPolicy, "Skip")
+	} else if err := tcc.reclaimPolicyManager.Sync(tc); err != nil {
+		return err
+	}
+
+	// works that should be done to make the pd cluster current state match the desired state:
+	// - create or update the pd service
+	// - create or update the pd headless service
+	// - create the pd statefulset
+	// - sync pd cluster status from pd to TidbCluster object
+	// - upgrade the pd cluster
+	// - scale out/in the pd cluster
+	// - failover the pd cluster
+	if opts.Skip[syncComponentPD] {
+		tcc.recordSyncOptionsSkip(tc, syncComponentPD, "Skip")
+	} else if err := tcc.pdMemberManager.Sync(tc); err != nil {
+		return err
+	}
+
+	// waiting for the pd cluster to pass the readiness gate of tc.Spec.ReadinessPolicy (see
+	// waitForPDClusterRunning) before bringing up tikv, unless
+	// the sync-options annotation forces PD onward regardless; tc.Status reflects the last real observed
+	// state whether or not PD's own sync ran this reconcile, so skipping PD's sync does not excuse it
+	// from the gate
+	if opts.Force[syncComponentPD] {
+		tcc.recordSyncOptionsForce(tc, syncComponentPD)
+	} else if err := tcc.waitForPDClusterRunning(tc); err != nil {
+		return err
+	}
+
+	// works that should be done to make the tikv cluster current state match the desired state:
+	// - create or update tikv headless service
+	// - create the tikv statefulset
+	// - sync tikv cluster status from pd to TidbCluster object
+	// - set the tikv cluster's leader/follower storage
+	// - upgrade the tikv cluster
+	// - scale out/in the tikv cluster
+	// - failover the tikv cluster
+	if opts.Skip[syncComponentTiKV] {
+		tcc.recordSyncOptionsSkip(tc, syncComponentTiKV, "Skip")
+	} else if err := tcc.tikvMemberManager.Sync(tc); err != nil {
+		return err
+	}
+
+	// waiting for the tikv cluster to pass the readiness gate of tc.Spec.ReadinessPolicy (see
+	// waitForTiKVClusterRunning) before bringing up tidb, unless the
+	// sync-options annotation forces TiKV onward regardless; tc.Status reflects the last real observed
+	// state whether or not TiKV's own sync ran this reconcile, so skipping TiKV's sync does not excuse it
+	// from the gate
+	if opts.Force[syncComponentTiKV] {
+		tcc.recordSyncOptionsForce(tc, syncComponentTiKV)
+	} else if err := tcc.waitForTiKVClusterRunning(tc); err != nil {
+		return err
+	}
+
+	// works that should be done to make the tidb cluster current state match the desired state:
+	// - create or update tidb headless service
+	// - create the tidb statefulset
+	// - sync tidb cluster status from tidb to TidbCluster object
+	// - upgrade the tidb cluster
+	// - scale out/in the tidb cluster
+	// - failover the tidb cluster
+	if opts.Skip[syncComponentTiDB] {
+		tcc.recordSyncOptionsSkip(tc, syncComponentTiDB, "Skip")
+	} else if err := tcc.tidbMemberManager.Sync(tc); err != nil {
+		return err
+	}
+
+	// syncing the labels from Pod to PV/PVC, these labels include:
+	// - store id label added by pd
+	// - member id label added by tidb
+	if opts.Skip[syncComponentMeta] {
+		tcc.recordSyncOptionsSkip(tc, syncComponentMeta, "Skip")
+	} else if err := tcc.metaManager.Sync(tc); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// recordSyncOptionsSkip records an event noting that a component's sync was bypassed for this reconcile
+// because of the pingcap.com/sync-options annotation.
+func (tcc *defaultTidbClusterControl) recordSyncOptionsSkip(tc *v1alpha1.TidbCluster, component, directive string) {
+	tcc.recorder.Eventf(tc, corev1.EventTypeNormal, "SyncOptionsSkip", "skipped %s sync due to %s annotation (%s)", component, SyncOptionsAnnotation, directive)
+}
+
+// recordSyncOptionsForce records an event noting that a component's readiness gate was bypassed for this
+// reconcile because of the pingcap.com/sync-options annotation.
+func (tcc *defaultTidbClusterControl) recordSyncOptionsForce(tc *v1alpha1.TidbCluster, component string) {
+	tcc.recorder.Eventf(tc, corev1.EventTypeNormal, "SyncOptionsForce", "forced %s past its readiness gate due to %s annotation (Force)", component, SyncOptionsAnnotation)
+}
+
+// waitForPDClusterRunning returns a requeue error until the PD cluster satisfies tc.Spec.ReadinessPolicy:
+// by default, every PD replica must be healthy and the StatefulSet must have caught up; with
+// PDQuorumOnly set, only a majority of replicas need be healthy.
+func (tcc *defaultTidbClusterControl) waitForPDClusterRunning(tc *v1alpha1.TidbCluster) error {
+	replicas := tc.Spec.PD.Replicas
+	members := tc.Status.PD.Members
+	policy := tc.Spec.ReadinessPolicy
+
+	requiredHealthy := replicas
+	if policy.PDQuorumOnly {
+		requiredHealthy = replicas/2 + 1
+	} else if int32(len(members)) != replicas {
+		return controller.RequeueErrorf("TidbCluster: %s/%s, waiting for PD cluster running", tc.Namespace, tc.Name)
+	}
+
+	if healthyPDMemberCount(members) < requiredHealthy {
+		return controller.RequeueErrorf("TidbCluster: %s/%s, waiting for PD cluster running", tc.Namespace, tc.Name)
+	}
+	if tc.Status.PD.StatefulSet == nil || tc.Status.PD.StatefulSet.ReadyReplicas < requiredHealthy {
+		return controller.RequeueErrorf("TidbCluster: %s/%s, waiting for PD cluster running", tc.Namespace, tc.Name)
+	}
+	return nil
+}
+
+// waitForTiKVClusterRunning returns a requeue error until the TiKV cluster satisfies
+// tc.Spec.ReadinessPolicy: by default, every TiKV store must be up and the StatefulSet must have caught
+// up; TiKVMinAvailable tolerates a configurable number of down stores, and SkipTiKVGate bypasses the
+// check entirely for PD-only clusters.
+func (tcc *defaultTidbClusterControl) waitForTiKVClusterRunning(tc *v1alpha1.TidbCluster) error {
+	policy := tc.Spec.ReadinessPolicy
+	if policy.SkipTiKVGate {
+		return nil
+	}
+
+	replicas := tc.Spec.TiKV.Replicas
+	stores := tc.Status.TiKV.Stores
+
+	minAvailable := replicas
+	if !isZeroIntOrString(policy.TiKVMinAvailable) {
+		v, err := intstr.GetScaledValueFromIntOrPercent(&policy.TiKVMinAvailable, int(replicas), true)
+		if err != nil {
+			return fmt.Errorf("TidbCluster: %s/%s, invalid spec.readinessPolicy.tikvMinAvailable %q: %v", tc.Namespace, tc.Name, policy.TiKVMinAvailable.String(), err)
+		}
+		minAvailable = int32(v)
+	} else if int32(len(stores)) != replicas {
+		return controller.RequeueErrorf("TidbCluster: %s/%s, waiting for TiKV cluster running", tc.Namespace, tc.Name)
+	}
+
+	if availableTiKVStoreCount(stores) < minAvailable {
+		return controller.RequeueErrorf("TidbCluster: %s/%s, waiting for TiKV cluster running", tc.Namespace, tc.Name)
+	}
+	if tc.Status.TiKV.StatefulSet == nil || tc.Status.TiKV.StatefulSet.ReadyReplicas < minAvailable {
+		return controller.RequeueErrorf("TidbCluster: %s/%s, waiting for TiKV cluster running", tc.Namespace, tc.Name)
+	}
+	return nil
+}
+
+// isZeroIntOrString reports whether v is the unset zero value of intstr.IntOrString.
+func isZeroIntOrString(v intstr.IntOrString) bool {
+	return v.Type == intstr.Int && v.IntVal == 0 && v.StrVal == ""
+}
+
+func healthyPDMemberCount(members map[string]v1alpha1.PDMember) int32 {
+	var count int32
+	for _, m := range members {
+		if m.Health {
+			count++
+		}
+	}
+	return count
+}
+
+func availableTiKVStoreCount(stores map[string]v1alpha1.TiKVStore) int32 {
+	var count int32
+	for _, store := range stores {
+		if store.State == v1alpha1.TiKVStateUp {
+			count++
+		}
+	}
+	return count
+}
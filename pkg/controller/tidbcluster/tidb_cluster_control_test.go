@@ -31,6 +31,7 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 )
 
@@ -40,21 +41,26 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 	type testcase struct {
 		name                     string
 		update                   func(cluster *v1alpha1.TidbCluster)
+		annotations              map[string]string
 		syncReclaimPolicyErr     bool
 		syncPDMemberManagerErr   bool
 		syncTiKVMemberManagerErr bool
 		syncTiDBMemberManagerErr bool
 		syncMetaManagerErr       bool
 		errExpectFn              func(*GomegaWithT, error)
+		recorderExpectFn         func(*GomegaWithT, *record.FakeRecorder)
 	}
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
 
 		tc := newTidbClusterForTidbClusterControl()
+		if test.annotations != nil {
+			tc.Annotations = test.annotations
+		}
 		if test.update != nil {
 			test.update(tc)
 		}
-		control, reclaimPolicyManager, pdMemberManager, tikvMemberManager, tidbMemberManager, metaManager := newFakeTidbClusterControl()
+		control, reclaimPolicyManager, pdMemberManager, tikvMemberManager, tidbMemberManager, metaManager, recorder := newFakeTidbClusterControl()
 
 		if test.syncReclaimPolicyErr {
 			reclaimPolicyManager.SetSyncError(fmt.Errorf("reclaim policy sync error"))
@@ -76,6 +82,9 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 		if test.errExpectFn != nil {
 			test.errExpectFn(g, err)
 		}
+		if test.recorderExpectFn != nil {
+			test.recorderExpectFn(g, recorder)
+		}
 	}
 	tests := []testcase{
 		{
@@ -253,6 +262,227 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 				g.Expect(strings.Contains(err.Error(), "waiting for TiKV cluster running")).To(Equal(true))
 			},
 		},
+		{
+			name: "pd quorum only policy unblocks sync when majority of members is healthy",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Spec.ReadinessPolicy.PDQuorumOnly = true
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+					"pd-1": {Name: "pd-1", Health: true},
+					"pd-2": {Name: "pd-2", Health: false},
+				}
+				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 2}
+				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+				}
+				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+			},
+			syncReclaimPolicyErr:     false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name: "tikv min available policy unblocks sync with a down store",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Spec.ReadinessPolicy.TiKVMinAvailable = intstr.FromInt(2)
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+					"pd-1": {Name: "pd-1", Health: true},
+					"pd-2": {Name: "pd-2", Health: true},
+				}
+				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateDown},
+				}
+				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 2}
+			},
+			syncReclaimPolicyErr:     false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name: "tikv min available policy with a malformed percent is rejected, not silently ignored",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Spec.ReadinessPolicy.TiKVMinAvailable = intstr.FromString("not-a-percent")
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+					"pd-1": {Name: "pd-1", Health: true},
+					"pd-2": {Name: "pd-2", Health: true},
+				}
+				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+				}
+				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+			},
+			syncReclaimPolicyErr:     false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "invalid spec.readinessPolicy.tikvMinAvailable")).To(Equal(true))
+			},
+		},
+		{
+			name: "skip tikv gate policy unblocks sync for a pd-only cluster",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Spec.ReadinessPolicy.SkipTiKVGate = true
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+					"pd-1": {Name: "pd-1", Health: true},
+					"pd-2": {Name: "pd-2", Health: true},
+				}
+				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+			},
+			syncReclaimPolicyErr:     false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name:        "sync-options skip tikv and meta",
+			annotations: map[string]string{SyncOptionsAnnotation: "Skip=tikv,meta"},
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+					"pd-1": {Name: "pd-1", Health: true},
+					"pd-2": {Name: "pd-2", Health: true},
+				}
+				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+				// Skip only bypasses TiKV's own sync, not the readiness gate that guards TiDB/meta, so
+				// the gate still needs a healthy TiKV status from a prior reconcile to pass here.
+				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+				}
+				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+			},
+			syncReclaimPolicyErr:     false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: true,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			recorderExpectFn: func(g *GomegaWithT, recorder *record.FakeRecorder) {
+				g.Expect(<-recorder.Events).To(ContainSubstring("skipped tikv sync"))
+				g.Expect(<-recorder.Events).To(ContainSubstring("skipped meta sync"))
+			},
+		},
+		{
+			name:        "sync-options skip tikv does not also bypass the tikv readiness gate",
+			annotations: map[string]string{SyncOptionsAnnotation: "Skip=tikv"},
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+					"pd-1": {Name: "pd-1", Health: true},
+					"pd-2": {Name: "pd-2", Health: true},
+				}
+				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+				// no TiKV status seeded: without an explicit Force=tikv, skipping TiKV's sync must not
+				// let TiDB/meta sync against an unknown TiKV state.
+			},
+			syncReclaimPolicyErr:     false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "waiting for TiKV cluster running")).To(Equal(true))
+			},
+			recorderExpectFn: func(g *GomegaWithT, recorder *record.FakeRecorder) {
+				g.Expect(<-recorder.Events).To(ContainSubstring("skipped tikv sync"))
+			},
+		},
+		{
+			name:        "sync-options force pd past the readiness gate",
+			annotations: map[string]string{SyncOptionsAnnotation: "Force=pd"},
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+				}
+				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+				}
+				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+			},
+			syncReclaimPolicyErr:     false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			recorderExpectFn: func(g *GomegaWithT, recorder *record.FakeRecorder) {
+				g.Expect(<-recorder.Events).To(ContainSubstring("forced pd past its readiness gate"))
+			},
+		},
+		{
+			name:                 "sync-options validate=false short-circuits reclaim policy checks",
+			annotations:          map[string]string{SyncOptionsAnnotation: "Validate=false"},
+			syncReclaimPolicyErr: true,
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+					"pd-1": {Name: "pd-1", Health: true},
+					"pd-2": {Name: "pd-2", Health: true},
+				}
+				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+				}
+				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+			},
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			recorderExpectFn: func(g *GomegaWithT, recorder *record.FakeRecorder) {
+				g.Expect(<-recorder.Events).To(ContainSubstring("skipped reclaim policy validation"))
+			},
+		},
+		{
+			name:        "sync-options invalid directive is rejected",
+			annotations: map[string]string{SyncOptionsAnnotation: "Bogus=nope"},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "unknown key")).To(Equal(true))
+			},
+		},
 		{
 			name: "tidb member manager sync error",
 			update: func(cluster *v1alpha1.TidbCluster) {
@@ -337,6 +567,104 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 	}
 }
 
+func TestTidbClusterControlDryRunUpdateTidbCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+		"pd-1": {Name: "pd-1", Health: true},
+		"pd-2": {Name: "pd-2", Health: true},
+	}
+	tc.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+		"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+		"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+
+	control, _, _, _, _, _, _ := newFakeTidbClusterControl()
+
+	tcBefore := tc.DeepCopy()
+	diff, err := control.DryRunUpdateTidbCluster(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(diff).NotTo(BeNil())
+
+	// a dry run must never mutate the TidbCluster object passed in
+	g.Expect(apiequality.Semantic.DeepEqual(tc, tcBefore)).To(Equal(true))
+}
+
+func TestTidbClusterControlDryRunUpdateTidbClusterPlansManagerMutations(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+		"pd-1": {Name: "pd-1", Health: true},
+		"pd-2": {Name: "pd-2", Health: true},
+	}
+	tc.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+		"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
+		"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+
+	// every real member/meta manager fake implements Planner, so a dry run surfaces all five of their
+	// diffs, not just a hand-picked one.
+	control, _, _, _, _, _, _ := newFakeTidbClusterControl()
+
+	diff, err := control.DryRunUpdateTidbCluster(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(diff.ReclaimPolicy.Updated).To(HaveLen(1))
+	g.Expect(diff.PD.Added).To(HaveLen(1))
+	g.Expect(diff.TiKV.Added).To(HaveLen(1))
+	g.Expect(diff.TiDB.Added).To(HaveLen(1))
+	g.Expect(diff.Meta.Updated).To(HaveLen(1))
+}
+
+func TestTidbClusterControlDryRunUpdateTidbClusterNotReady(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+	}
+
+	control, _, _, _, _, _, _ := newFakeTidbClusterControl()
+
+	diff, err := control.DryRunUpdateTidbCluster(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(strings.Contains(err.Error(), "waiting for PD cluster running")).To(Equal(true))
+	// the partial diff computed before the blocking stage is still returned for inspection
+	g.Expect(diff).NotTo(BeNil())
+	g.Expect(diff.PD).NotTo(BeNil())
+}
+
+func TestParseSyncOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTidbClusterControl()
+	opts, err := ParseSyncOptions(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(opts.Skip).To(BeEmpty())
+	g.Expect(opts.Force).To(BeEmpty())
+	g.Expect(opts.Validate).To(Equal(true))
+
+	tc.Annotations = map[string]string{SyncOptionsAnnotation: "Skip=tikv,meta;Force=pd;Validate=false"}
+	opts, err = ParseSyncOptions(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(opts.Skip).To(Equal(map[string]bool{"tikv": true, "meta": true}))
+	g.Expect(opts.Force).To(Equal(map[string]bool{"pd": true}))
+	g.Expect(opts.Validate).To(Equal(false))
+
+	tc.Annotations = map[string]string{SyncOptionsAnnotation: "malformed"}
+	_, err = ParseSyncOptions(tc)
+	g.Expect(err).To(HaveOccurred())
+}
+
 func TestTidbClusterStatusEquality(t *testing.T) {
 	g := NewGomegaWithT(t)
 	tcStatus := v1alpha1.TidbClusterStatus{}
@@ -350,7 +678,7 @@ func TestTidbClusterStatusEquality(t *testing.T) {
 	g.Expect(apiequality.Semantic.DeepEqual(&tcStatus, tcStatusCopy)).To(Equal(false))
 }
 
-func newFakeTidbClusterControl() (ControlInterface, *meta.FakeReclaimPolicyManager, *mm.FakePDMemberManager, *mm.FakeTiKVMemberManager, *mm.FakeTiDBMemberManager, *meta.FakeMetaManager) {
+func newFakeTidbClusterControl() (ControlInterface, *meta.FakeReclaimPolicyManager, *mm.FakePDMemberManager, *mm.FakeTiKVMemberManager, *mm.FakeTiDBMemberManager, *meta.FakeMetaManager, *record.FakeRecorder) {
 	cli := fake.NewSimpleClientset()
 	tcInformer := informers.NewSharedInformerFactory(cli, 0).Pingcap().V1alpha1().TidbClusters()
 	recorder := record.NewFakeRecorder(10)
@@ -364,7 +692,7 @@ func newFakeTidbClusterControl() (ControlInterface, *meta.FakeReclaimPolicyManag
 	opc := mm.NewFakeOrphanPodsCleaner()
 	control := NewDefaultTidbClusterControl(tcControl, pdMemberManager, tikvMemberManager, tidbMemberManager, reclaimPolicyManager, metaManager, opc, recorder)
 
-	return control, reclaimPolicyManager, pdMemberManager, tikvMemberManager, tidbMemberManager, metaManager
+	return control, reclaimPolicyManager, pdMemberManager, tikvMemberManager, tidbMemberManager, metaManager, recorder
 }
 
 func syncTidbClusterControl(tc *v1alpha1.TidbCluster, _ *controller.FakeStatefulSetControl, control ControlInterface) error {
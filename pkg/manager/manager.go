@@ -0,0 +1,42 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Manager implements the logic for syncing a TidbCluster's members (PD, TiKV, TiDB), its reclaim policy,
+// or its label metadata. It is implemented as an interface so that pkg/controller/tidbcluster can drive
+// every component through the same Sync call.
+type Manager interface {
+	Sync(*v1alpha1.TidbCluster) error
+}
+
+// ComponentDiff describes the StatefulSet/Service/ConfigMap mutations a single Manager intends to make,
+// without having made them. It lives here, rather than alongside the orchestrator that consumes it in
+// pkg/controller/tidbcluster, so that member/meta manager implementations can return it from Plan without
+// importing the package that imports them.
+type ComponentDiff struct {
+	Added   []runtime.Object
+	Removed []runtime.Object
+	Updated []runtime.Object
+}
+
+// Planner is implemented by a Manager that supports computing its intended mutations without applying
+// them, so a caller can preview a dry run before invoking Sync.
+type Planner interface {
+	Plan(tc *v1alpha1.TidbCluster) (*ComponentDiff, error)
+}
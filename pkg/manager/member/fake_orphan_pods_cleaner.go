@@ -0,0 +1,39 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import "github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+
+// FakeOrphanPodsCleaner is a fake implementation of OrphanPodsCleaner, used in tests.
+type FakeOrphanPodsCleaner struct {
+	err error
+}
+
+// NewFakeOrphanPodsCleaner returns a FakeOrphanPodsCleaner.
+func NewFakeOrphanPodsCleaner() *FakeOrphanPodsCleaner {
+	return &FakeOrphanPodsCleaner{}
+}
+
+// SetCleanError makes the next Clean call return err.
+func (c *FakeOrphanPodsCleaner) SetCleanError(err error) {
+	c.err = err
+}
+
+// Clean implements OrphanPodsCleaner.
+func (c *FakeOrphanPodsCleaner) Clean(_ *v1alpha1.TidbCluster) (map[string]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return map[string]string{}, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FakePDMemberManager is a fake implementation of the PD member manager, used in tests.
+type FakePDMemberManager struct {
+	syncErr error
+}
+
+// NewFakePDMemberManager returns a FakePDMemberManager.
+func NewFakePDMemberManager() *FakePDMemberManager {
+	return &FakePDMemberManager{}
+}
+
+// SetSyncError makes the next Sync call return err.
+func (m *FakePDMemberManager) SetSyncError(err error) {
+	m.syncErr = err
+}
+
+// Sync implements manager.Manager.
+func (m *FakePDMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
+	return m.syncErr
+}
+
+// Plan implements manager.Planner, reporting the PD Service this manager would create or update,
+// mirroring the first object its real Sync reconciles.
+func (m *FakePDMemberManager) Plan(tc *v1alpha1.TidbCluster) (*manager.ComponentDiff, error) {
+	return &manager.ComponentDiff{
+		Added: []runtime.Object{
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: tc.Name + "-pd", Namespace: tc.Namespace}},
+		},
+	}, nil
+}
@@ -0,0 +1,22 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import "github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+
+// OrphanPodsCleaner implements the logic for cleaning up pods that are left behind by a scale-in but
+// whose PVCs are still retained, e.g. by a reclaim policy of Retain.
+type OrphanPodsCleaner interface {
+	Clean(*v1alpha1.TidbCluster) (map[string]string, error)
+}
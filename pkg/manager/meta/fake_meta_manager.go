@@ -0,0 +1,52 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FakeMetaManager is a fake implementation of the meta manager, used in tests.
+type FakeMetaManager struct {
+	syncErr error
+}
+
+// NewFakeMetaManager returns a FakeMetaManager.
+func NewFakeMetaManager() *FakeMetaManager {
+	return &FakeMetaManager{}
+}
+
+// SetSyncError makes the next Sync call return err.
+func (m *FakeMetaManager) SetSyncError(err error) {
+	m.syncErr = err
+}
+
+// Sync implements manager.Manager.
+func (m *FakeMetaManager) Sync(tc *v1alpha1.TidbCluster) error {
+	return m.syncErr
+}
+
+// Plan implements manager.Planner, reporting the PVC label updates this manager would make to propagate
+// store/member IDs from Pods onto their PVs/PVCs.
+func (m *FakeMetaManager) Plan(tc *v1alpha1.TidbCluster) (*manager.ComponentDiff, error) {
+	return &manager.ComponentDiff{
+		Updated: []runtime.Object{
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: tc.Name + "-pvc"}},
+		},
+	}, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap.com/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FakeReclaimPolicyManager is a fake implementation of the reclaim policy manager, used in tests.
+type FakeReclaimPolicyManager struct {
+	syncErr error
+}
+
+// NewFakeReclaimPolicyManager returns a FakeReclaimPolicyManager.
+func NewFakeReclaimPolicyManager() *FakeReclaimPolicyManager {
+	return &FakeReclaimPolicyManager{}
+}
+
+// SetSyncError makes the next Sync call return err.
+func (m *FakeReclaimPolicyManager) SetSyncError(err error) {
+	m.syncErr = err
+}
+
+// Sync implements manager.Manager.
+func (m *FakeReclaimPolicyManager) Sync(tc *v1alpha1.TidbCluster) error {
+	return m.syncErr
+}
+
+// Plan implements manager.Planner, reporting the PVs this manager would flip to the cluster's configured
+// reclaim policy.
+func (m *FakeReclaimPolicyManager) Plan(tc *v1alpha1.TidbCluster) (*manager.ComponentDiff, error) {
+	return &manager.ComponentDiff{
+		Updated: []runtime.Object{
+			&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: tc.Name + "-pv"}},
+		},
+	}, nil
+}